@@ -0,0 +1,111 @@
+package logger
+
+import (
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/buffer"
+	"go.uber.org/zap/zapcore"
+)
+
+var bufferPool = buffer.NewPool()
+
+func init() {
+	if err := zap.RegisterEncoder(string(FormatCBOR), newCBOREncoder); err != nil {
+		panic(err)
+	}
+}
+
+// cborEncoder implements zapcore.Encoder by accumulating fields into a zapcore.MapObjectEncoder,
+// the same way zap's own encoders do, and CBOR-marshaling the result for each entry. Building on
+// MapObjectEncoder keeps all of zap's field-type handling (arrays, objects, reflected values, ...)
+// for free, instead of reimplementing ObjectEncoder from scratch.
+type cborEncoder struct {
+	cfg zapcore.EncoderConfig
+	*zapcore.MapObjectEncoder
+}
+
+func newCBOREncoder(cfg zapcore.EncoderConfig) (zapcore.Encoder, error) {
+	return &cborEncoder{cfg: cfg, MapObjectEncoder: zapcore.NewMapObjectEncoder()}, nil
+}
+
+// Clone implements zapcore.Encoder.
+func (enc *cborEncoder) Clone() zapcore.Encoder {
+	clone := zapcore.NewMapObjectEncoder()
+	for k, v := range enc.Fields {
+		clone.Fields[k] = v
+	}
+	return &cborEncoder{cfg: enc.cfg, MapObjectEncoder: clone}
+}
+
+// EncodeEntry implements zapcore.Encoder. It uses ts/level/msg/caller/stack keys the same as
+// EncoderConfig, durations as integer nanoseconds and times as RFC3339Nano strings, so fields
+// survive the round trip with their original type instead of being stringified.
+func (enc *cborEncoder) EncodeEntry(ent zapcore.Entry, fields []zapcore.Field) (*buffer.Buffer, error) {
+	root := zapcore.NewMapObjectEncoder()
+	for k, v := range enc.Fields {
+		root.Fields[k] = v
+	}
+	for _, f := range fields {
+		f.AddTo(root)
+	}
+
+	m := make(map[string]interface{}, len(root.Fields)+6)
+	if enc.cfg.TimeKey != "" {
+		m[enc.cfg.TimeKey] = ent.Time.Format(time.RFC3339Nano)
+	}
+	if enc.cfg.LevelKey != "" {
+		m[enc.cfg.LevelKey] = ent.Level.String()
+	}
+	if enc.cfg.NameKey != "" && ent.LoggerName != "" {
+		m[enc.cfg.NameKey] = ent.LoggerName
+	}
+	if enc.cfg.CallerKey != "" && ent.Caller.Defined {
+		m[enc.cfg.CallerKey] = ent.Caller.TrimmedPath()
+	}
+	if enc.cfg.MessageKey != "" {
+		m[enc.cfg.MessageKey] = ent.Message
+	}
+	if enc.cfg.StacktraceKey != "" && ent.Stack != "" {
+		m[enc.cfg.StacktraceKey] = ent.Stack
+	}
+	for k, v := range root.Fields {
+		m[k] = sanitizeForCBOR(v)
+	}
+
+	data, err := cbor.Marshal(m)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := bufferPool.Get()
+	buf.Write(data)
+	return buf, nil
+}
+
+// sanitizeForCBOR normalizes the handful of types MapObjectEncoder stores as-is but that a CBOR
+// library has no special handling for: time.Duration becomes integer nanoseconds and time.Time
+// becomes an RFC3339Nano string, recursively through nested objects and arrays.
+func sanitizeForCBOR(v interface{}) interface{} {
+	switch val := v.(type) {
+	case time.Duration:
+		return int64(val)
+	case time.Time:
+		return val.Format(time.RFC3339Nano)
+	case map[string]interface{}:
+		out := make(map[string]interface{}, len(val))
+		for k, fv := range val {
+			out[k] = sanitizeForCBOR(fv)
+		}
+		return out
+	case []interface{}:
+		out := make([]interface{}, len(val))
+		for i, fv := range val {
+			out[i] = sanitizeForCBOR(fv)
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,82 @@
+package logger
+
+import (
+	"time"
+
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// TestCBOREncoderRoundTrip checks that an entry with a duration and a time field survives
+// EncodeEntry/cbor.Unmarshal with the types EncodeEntry promises: durations as integer nanoseconds
+// and times as RFC3339Nano strings, rather than whatever MapObjectEncoder happened to store.
+func TestCBOREncoderRoundTrip(t *testing.T) {
+	enc, err := newCBOREncoder(EncoderConfig)
+	if err != nil {
+		t.Fatalf("newCBOREncoder failed: %v", err)
+	}
+
+	at := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	fields := []zapcore.Field{
+		zap.Duration("elapsed", 2500*time.Millisecond),
+		zap.Time("at", at),
+		zap.String("msg_field", "value"),
+	}
+
+	ent := zapcore.Entry{
+		Level:      zapcore.InfoLevel,
+		Time:       at,
+		LoggerName: "test",
+		Message:    "hello",
+	}
+
+	buf, err := enc.EncodeEntry(ent, fields)
+	if err != nil {
+		t.Fatalf("EncodeEntry failed: %v", err)
+	}
+
+	var decoded map[string]interface{}
+	if err := cbor.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("cbor.Unmarshal failed: %v", err)
+	}
+
+	if decoded[EncoderConfig.MessageKey] != "hello" {
+		t.Fatalf("expected message to round-trip, got %v", decoded[EncoderConfig.MessageKey])
+	}
+	if decoded[EncoderConfig.TimeKey] != at.Format(time.RFC3339Nano) {
+		t.Fatalf("expected entry time as RFC3339Nano, got %v", decoded[EncoderConfig.TimeKey])
+	}
+
+	elapsed, ok := decoded["elapsed"].(uint64)
+	if !ok || int64(elapsed) != int64(2500*time.Millisecond) {
+		t.Fatalf("expected duration as integer nanoseconds, got %v (%T)", decoded["elapsed"], decoded["elapsed"])
+	}
+
+	if decoded["at"] != at.Format(time.RFC3339Nano) {
+		t.Fatalf("expected time field as RFC3339Nano, got %v", decoded["at"])
+	}
+}
+
+// TestCBOREncoderCloneIsIndependent checks that fields added to a clone do not leak back into the
+// encoder it was cloned from.
+func TestCBOREncoderCloneIsIndependent(t *testing.T) {
+	enc, err := newCBOREncoder(EncoderConfig)
+	if err != nil {
+		t.Fatalf("newCBOREncoder failed: %v", err)
+	}
+	base := enc.(*cborEncoder)
+	base.AddString("base_field", "base_value")
+
+	clone := base.Clone().(*cborEncoder)
+	clone.AddString("clone_field", "clone_value")
+
+	if _, ok := base.Fields["clone_field"]; ok {
+		t.Fatal("expected field added to the clone not to leak into the original encoder")
+	}
+	if _, ok := clone.Fields["base_field"]; !ok {
+		t.Fatal("expected the clone to carry fields that existed before it was cloned")
+	}
+}
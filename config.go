@@ -16,6 +16,10 @@ const (
 
 	// FormatJSON causes logs to be printed in JSON
 	FormatJSON Format = "json"
+
+	// FormatCBOR causes logs to be printed as self-delimiting CBOR maps, a binary format with
+	// substantially smaller payloads than JSON that still preserves field typing
+	FormatCBOR Format = "cbor"
 )
 
 // Config stores configuration of the logger
@@ -44,7 +48,7 @@ func ConfigureWithCLI(defaultConfig Config) Config {
 	var format string
 	flags := pflag.NewFlagSet("logger", pflag.ContinueOnError)
 	flags.ParseErrorsWhitelist.UnknownFlags = true
-	flags.StringVar(&format, "log-format", string(defaultConfig.Format), "Format of log output: console | json")
+	flags.StringVar(&format, "log-format", string(defaultConfig.Format), "Format of log output: console | json | cbor")
 	flags.BoolVarP(&defaultConfig.Verbose, "verbose", "v", defaultConfig.Verbose, "Turns on verbose logging")
 	// Dummy flag to turn off printing usage of this flag set
 	flags.BoolP("help", "h", false, "")
@@ -52,7 +56,7 @@ func ConfigureWithCLI(defaultConfig Config) Config {
 	_ = flags.Parse(os.Args[1:])
 
 	defaultConfig.Format = Format(format)
-	if defaultConfig.Format != FormatConsole && defaultConfig.Format != FormatJSON {
+	if defaultConfig.Format != FormatConsole && defaultConfig.Format != FormatJSON && defaultConfig.Format != FormatCBOR {
 		panic(fmt.Errorf("incorrect logging format %s", format))
 	}
 
@@ -62,6 +66,6 @@ func ConfigureWithCLI(defaultConfig Config) Config {
 // AddDummyFlags adds dummy flags defined by logger so your application does not complain about undefined flags
 // and help includes logging-specific options
 func AddDummyFlags(defaultConfig Config, flags *pflag.FlagSet) {
-	flags.String("log-format", string(defaultConfig.Format), "Format of log output: console | json")
+	flags.String("log-format", string(defaultConfig.Format), "Format of log output: console | json | cbor")
 	flags.BoolP("verbose", "v", defaultConfig.Verbose, "Turns on verbose logging")
 }
@@ -0,0 +1,29 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+func TestNewContextWiresLoggerLevelAndCores(t *testing.T) {
+	ctx := NewContext(context.Background(), ToolDefaultConfig)
+
+	if Get(ctx) == nil {
+		t.Fatal("expected Get to return the logger installed by NewContext")
+	}
+
+	if _, ok := GetLevel(ctx); !ok {
+		t.Fatal("expected GetLevel to return the level installed by NewContext")
+	}
+
+	cores, ok := GetCores(ctx)
+	if !ok {
+		t.Fatal("expected GetCores to return the registry installed by NewContext")
+	}
+
+	// The registry NewContext wired in is live: a core can be attached to it and removed again.
+	remove := cores.AddCore(zapcore.NewNopCore())
+	defer remove()
+}
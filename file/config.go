@@ -0,0 +1,13 @@
+package file
+
+import "time"
+
+// Config stores configuration of the file sink.
+type Config struct {
+	Path        string
+	PathPattern string
+	MaxSize     int64
+	MaxAge      time.Duration
+	MaxBackups  int
+	Compress    bool
+}
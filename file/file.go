@@ -0,0 +1,287 @@
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/outofforest/parallel"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/outofforest/logger"
+)
+
+// WithFile registers a core writing logs to a rotating file onto the MultiCore stored in ctx, so
+// file output composes with whatever else the logger already writes to (stderr, the remote sink,
+// ...). It works whether ctx was wired with logger.NewContext or with the bare
+// logger.WithLogger(ctx, logger.New(cfg).Logger) convention - see logger.AddCore.
+//
+// The caller must call the returned task, which unregisters the core on shutdown.
+func WithFile(ctx context.Context, config Config) (context.Context, parallel.Task) {
+	sink := newFileSink(config)
+
+	fileCore := zapcore.NewCore(zapcore.NewJSONEncoder(logger.EncoderConfig), sink, zap.NewAtomicLevelAt(zap.DebugLevel))
+	ctx, removeCore := logger.AddCore(ctx, fileCore)
+
+	return ctx, func(ctx context.Context) error {
+		defer removeCore()
+		return sink.Run(ctx)
+	}
+}
+
+// strftimeReplacer translates the handful of strftime directives PathPattern supports into the
+// equivalent Go reference-time layout.
+var strftimeReplacer = strings.NewReplacer(
+	"%Y", "2006",
+	"%m", "01",
+	"%d", "02",
+	"%H", "15",
+	"%M", "04",
+	"%S", "05",
+)
+
+// fileSink is a zapcore.WriteSyncer that rotates the underlying file by size and, when
+// PathPattern is used, whenever the formatted path changes.
+type fileSink struct {
+	config Config
+
+	mu          sync.Mutex
+	file        *os.File
+	currentPath string
+	size        int64
+}
+
+func newFileSink(config Config) *fileSink {
+	return &fileSink{config: config}
+}
+
+// Run prunes expired backups left over from a previous run on startup - so a restart with a low
+// write rate doesn't wait for the next rotation to sweep them - and then reopens the current file
+// whenever SIGHUP is received, the way log shippers signal an application after rotating its file
+// externally.
+func (s *fileSink) Run(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.prune(s.path())
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGHUP)
+	defer signal.Stop(sigCh)
+
+	for {
+		select {
+		case <-sigCh:
+			if err := s.reopen(); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return errors.WithStack(ctx.Err())
+		}
+	}
+}
+
+// Write implements zapcore.WriteSyncer.
+func (s *fileSink) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.ensureOpen(); err != nil {
+		return 0, err
+	}
+
+	if s.config.MaxSize > 0 && s.size+int64(len(p)) > s.config.MaxSize {
+		if err := s.rotate(); err != nil {
+			return 0, err
+		}
+		if err := s.open(s.path()); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := s.file.Write(p)
+	s.size += int64(n)
+	return n, errors.WithStack(err)
+}
+
+// Sync implements zapcore.WriteSyncer.
+func (s *fileSink) Sync() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+	return errors.WithStack(s.file.Sync())
+}
+
+func (s *fileSink) path() string {
+	if s.config.PathPattern != "" {
+		return time.Now().Format(strftimeReplacer.Replace(s.config.PathPattern))
+	}
+	return s.config.Path
+}
+
+// ensureOpen opens the current file if none is open yet, and rotates to it if PathPattern moved
+// on to a new path since the file was last opened (e.g. the day rolled over).
+func (s *fileSink) ensureOpen() error {
+	path := s.path()
+	if s.file != nil && path == s.currentPath {
+		return nil
+	}
+	if s.file != nil {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	return s.open(path)
+}
+
+func (s *fileSink) open(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return errors.WithStack(err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return errors.WithStack(err)
+	}
+
+	s.file = f
+	s.currentPath = path
+	s.size = info.Size()
+	return nil
+}
+
+// reopen closes the current file without renaming it, so the next write reopens whatever now
+// sits at the configured path. It is a no-op if no file is open yet.
+func (s *fileSink) reopen() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.file == nil {
+		return nil
+	}
+
+	err := s.file.Close()
+	s.file = nil
+	return errors.WithStack(err)
+}
+
+// rotate closes the current file, renames it aside with a timestamp suffix, optionally gzips it,
+// and prunes backups beyond MaxBackups or older than MaxAge. The caller is responsible for
+// reopening the file afterwards.
+func (s *fileSink) rotate() error {
+	if s.file == nil {
+		return nil
+	}
+
+	oldPath := s.currentPath
+	if err := s.file.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+	s.file = nil
+
+	backupPath := oldPath + "." + time.Now().Format("20060102T150405.000000000")
+	if err := os.Rename(oldPath, backupPath); err != nil {
+		return errors.WithStack(err)
+	}
+
+	if s.config.Compress {
+		if err := compressFile(backupPath); err != nil {
+			return err
+		}
+	}
+
+	return s.prune(oldPath)
+}
+
+func (s *fileSink) prune(basePath string) error {
+	dir := filepath.Dir(basePath)
+	prefix := filepath.Base(basePath) + "."
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			// Nothing has ever been written to this path yet, so there are no backups to prune -
+			// the case Run hits pruning on startup before the first rotation has created dir.
+			return nil
+		}
+		return errors.WithStack(err)
+	}
+
+	var backups []backupFile
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), prefix) {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		backups = append(backups, backupFile{path: filepath.Join(dir, e.Name()), modTime: info.ModTime()})
+	}
+
+	sort.Slice(backups, func(i, j int) bool { return backups[i].modTime.After(backups[j].modTime) })
+
+	now := time.Now()
+	for i, b := range backups {
+		expired := s.config.MaxAge > 0 && now.Sub(b.modTime) > s.config.MaxAge
+		overflow := s.config.MaxBackups > 0 && i >= s.config.MaxBackups
+		if !expired && !overflow {
+			continue
+		}
+		if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+			return errors.WithStack(err)
+		}
+	}
+
+	return nil
+}
+
+type backupFile struct {
+	path    string
+	modTime time.Time
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer src.Close()
+
+	dst, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer dst.Close()
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		return errors.WithStack(err)
+	}
+	if err := gz.Close(); err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(os.Remove(path))
+}
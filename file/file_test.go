@@ -0,0 +1,262 @@
+package file
+
+import (
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestWriteRotatesOnMaxSize checks that Write rotates the file aside, rather than letting it grow
+// unbounded, once MaxSize is exceeded, and that logging keeps working against the new file.
+func TestWriteRotatesOnMaxSize(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	s := newFileSink(Config{Path: path, MaxSize: 10})
+
+	if _, err := s.Write([]byte("12345")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if _, err := s.Write([]byte("1234567890")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("expected the original file plus one rotated backup, got %d entries", len(entries))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "1234567890" {
+		t.Fatalf("expected current file to hold only the write that triggered rotation, got %q", data)
+	}
+}
+
+// TestPruneRemovesBackupsBeyondMaxBackups checks that rotating past MaxBackups deletes the oldest
+// backups instead of keeping them forever.
+func TestPruneRemovesBackupsBeyondMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	s := newFileSink(Config{Path: path, MaxSize: 1, MaxBackups: 1})
+
+	for i := 0; i < 3; i++ {
+		if _, err := s.Write([]byte("xx")); err != nil {
+			t.Fatalf("Write failed: %v", err)
+		}
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	backups := 0
+	for _, e := range entries {
+		if e.Name() != "app.log" {
+			backups++
+		}
+	}
+	if backups != 1 {
+		t.Fatalf("expected pruning to keep only 1 backup, got %d (entries: %v)", backups, entries)
+	}
+}
+
+// TestPruneRemovesBackupsBeyondMaxAge checks that prune removes a backup older than MaxAge even
+// though MaxBackups (unset here) would otherwise keep it.
+func TestPruneRemovesBackupsBeyondMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	s := newFileSink(Config{Path: path})
+
+	if _, err := s.Write([]byte("xx")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := s.rotate(); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("expected one backup after rotating, got %d", len(entries))
+	}
+	backup := filepath.Join(dir, entries[0].Name())
+	oldTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(backup, oldTime, oldTime); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	s.config.MaxAge = time.Minute
+	if err := s.prune(path); err != nil {
+		t.Fatalf("prune failed: %v", err)
+	}
+
+	if _, err := os.Stat(backup); !os.IsNotExist(err) {
+		t.Fatalf("expected backup older than MaxAge to be removed, stat err: %v", err)
+	}
+}
+
+// TestRotateCompressesBackupWhenConfigured checks that, with Compress set, the renamed-aside
+// backup ends up gzipped rather than a plain copy.
+func TestRotateCompressesBackupWhenConfigured(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	s := newFileSink(Config{Path: path, Compress: true})
+	if _, err := s.Write([]byte("hello")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+	if err := s.rotate(); err != nil {
+		t.Fatalf("rotate failed: %v", err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+
+	var gz string
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".gz" {
+			gz = filepath.Join(dir, e.Name())
+		}
+	}
+	if gz == "" {
+		t.Fatalf("expected a .gz backup among %v", entries)
+	}
+
+	f, err := os.Open(gz)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatalf("gzip.NewReader failed: %v", err)
+	}
+	defer r.Close()
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("reading gzip content failed: %v", err)
+	}
+	if string(data) != "hello" {
+		t.Fatalf("expected decompressed backup to equal the written content, got %q", data)
+	}
+}
+
+// TestEnsureOpenRotatesOnPathPatternChange checks that, with PathPattern in use, writing after the
+// formatted path has moved on rotates the previous file instead of silently keeping writing to it.
+func TestEnsureOpenRotatesOnPathPatternChange(t *testing.T) {
+	dir := t.TempDir()
+
+	s := newFileSink(Config{PathPattern: filepath.Join(dir, "app-%Y%m%d.log")})
+	if err := s.open(filepath.Join(dir, "app-19700101.log")); err != nil {
+		t.Fatalf("open failed: %v", err)
+	}
+	s.currentPath = filepath.Join(dir, "app-19700101.log")
+
+	if err := s.ensureOpen(); err != nil {
+		t.Fatalf("ensureOpen failed: %v", err)
+	}
+
+	if s.currentPath == filepath.Join(dir, "app-19700101.log") {
+		t.Fatal("expected ensureOpen to roll over to the current pattern-formatted path")
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir failed: %v", err)
+	}
+	found := false
+	for _, e := range entries {
+		if strings.HasPrefix(e.Name(), "app-19700101.log.") {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected the stale file to have been renamed aside as a rotated backup, entries: %v", entries)
+	}
+}
+
+// TestReopenClosesFileForNextWriteToReopen checks that reopen (the SIGHUP path) closes the file
+// handle without renaming anything, so the next Write reopens whatever now sits at the path -
+// the behavior external log rotation (logrotate, ...) relies on.
+func TestReopenClosesFileForNextWriteToReopen(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	s := newFileSink(Config{Path: path})
+	if _, err := s.Write([]byte("before")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	if err := s.reopen(); err != nil {
+		t.Fatalf("reopen failed: %v", err)
+	}
+	if s.file != nil {
+		t.Fatal("expected reopen to close the file handle")
+	}
+
+	if err := os.Rename(path, path+".external"); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+
+	if _, err := s.Write([]byte("after")); err != nil {
+		t.Fatalf("Write failed: %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile failed: %v", err)
+	}
+	if string(data) != "after" {
+		t.Fatalf("expected Write after reopen to create a fresh file, got %q", data)
+	}
+}
+
+// TestRunPrunesStaleBackupsOnStartup checks that Run sweeps backups left over from a previous run
+// that are already past MaxBackups/MaxAge as soon as it starts, rather than waiting for the next
+// rotation - which may never come if the write rate is low.
+func TestRunPrunesStaleBackupsOnStartup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "app.log")
+
+	stale := path + ".20060102T150405.000000000"
+	if err := os.WriteFile(stale, []byte("old"), 0o644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	oldTime := time.Now().Add(-time.Hour)
+	if err := os.Chtimes(stale, oldTime, oldTime); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+
+	s := newFileSink(Config{Path: path, MaxAge: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Run(ctx); err == nil {
+		t.Fatal("expected Run to return once ctx is done")
+	}
+
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Fatalf("expected the stale backup to be pruned on startup, stat err: %v", err)
+	}
+}
@@ -0,0 +1,65 @@
+package logger
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestLevelHandlerServesGetAndPut checks that LevelHandler reports the current level on GET and
+// applies a new one on PUT, against the same zap.AtomicLevel SetLevel changes.
+func TestLevelHandlerServesGetAndPut(t *testing.T) {
+	ctx := NewContext(context.Background(), ToolDefaultConfig)
+
+	rec := httptest.NewRecorder()
+	LevelHandler(ctx).ServeHTTP(rec, httptest.NewRequest("GET", "/", nil))
+	if !strings.Contains(rec.Body.String(), "info") {
+		t.Fatalf("expected GET to report the initial info level, got %q", rec.Body.String())
+	}
+
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest("PUT", "/", strings.NewReader(`{"level":"debug"}`))
+	LevelHandler(ctx).ServeHTTP(rec, req)
+
+	level, ok := GetLevel(ctx)
+	if !ok || level.Level() != zapcore.DebugLevel {
+		t.Fatalf("expected PUT to change the level to debug, got %v (ok: %v)", level.Level(), ok)
+	}
+}
+
+// TestSetLevelChangesLevel checks that SetLevel changes the severity of the atomic level wired
+// into ctx, the way LevelHandler's PUT handler does.
+func TestSetLevelChangesLevel(t *testing.T) {
+	ctx := NewContext(context.Background(), ToolDefaultConfig)
+
+	SetLevel(ctx, zapcore.ErrorLevel)
+
+	level, ok := GetLevel(ctx)
+	if !ok || level.Level() != zapcore.ErrorLevel {
+		t.Fatalf("expected SetLevel to change the level to error, got %v (ok: %v)", level.Level(), ok)
+	}
+}
+
+// TestSetLevelPanicsWithoutConfiguredLevel and TestLevelHandlerPanicsWithoutConfiguredLevel check
+// that both panic with a clear message, rather than silently no-oping, against a context that was
+// never wired with WithLevel.
+func TestSetLevelPanicsWithoutConfiguredLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected SetLevel to panic without a configured level")
+		}
+	}()
+	SetLevel(context.Background(), zapcore.DebugLevel)
+}
+
+func TestLevelHandlerPanicsWithoutConfiguredLevel(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected LevelHandler to panic without a configured level")
+		}
+	}()
+	LevelHandler(context.Background())
+}
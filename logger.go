@@ -2,6 +2,8 @@ package logger
 
 import (
 	"context"
+	"net/http"
+	"sync"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -9,7 +11,12 @@ import (
 
 type logFiedType int
 
-const logField logFiedType = iota
+const (
+	logField logFiedType = iota
+	levelField
+	coresField
+	fieldsField
+)
 
 // EncoderConfig is the config of log encoder.
 var EncoderConfig = zapcore.EncoderConfig{
@@ -27,30 +34,109 @@ var EncoderConfig = zapcore.EncoderConfig{
 	EncodeCaller:   zapcore.ShortCallerEncoder,
 }
 
+// Logger bundles a *zap.Logger with the zap.AtomicLevel controlling its severity and the
+// MultiCore acting as its root sink registry, so both may be adjusted at runtime after
+// construction.
+type Logger struct {
+	*zap.Logger
+	Level zap.AtomicLevel
+	Cores *MultiCore
+}
+
 // New creates new logger.
-func New(config Config) *zap.Logger {
+func New(config Config) *Logger {
+	level := zap.NewAtomicLevelAt(zap.InfoLevel)
+	if config.Verbose {
+		level = zap.NewAtomicLevelAt(zap.DebugLevel)
+	}
+
 	cfg := zap.Config{
-		Level:            zap.NewAtomicLevelAt(zap.InfoLevel),
+		Level:            level,
 		Development:      true,
 		Encoding:         string(config.Format),
 		EncoderConfig:    EncoderConfig,
 		OutputPaths:      []string{"stderr"},
 		ErrorOutputPaths: []string{"stderr"},
 	}
-	if config.Verbose {
-		cfg.Level = zap.NewAtomicLevelAt(zap.DebugLevel)
-	}
 
 	log, err := cfg.Build()
 	if err != nil {
 		panic(err)
 	}
-	return log
+
+	cores := NewMultiCore(log.Core())
+	log = log.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+		return cores
+	}))
+
+	return &Logger{Logger: log, Level: level, Cores: cores}
+}
+
+// NewContext builds a logger from config and wires it, together with its atomic level and its
+// core registry, into a context derived from ctx - in one call, so SetLevel, LevelHandler and
+// AddCore (and by extension remote.WithRemote, file.WithFile, ...) work against the returned
+// context without the caller having to separately remember WithLogger, WithLevel and WithCores.
+func NewContext(ctx context.Context, config Config) context.Context {
+	log := New(config)
+	ctx = WithLogger(ctx, log.Logger)
+	ctx = WithLevel(ctx, log.Level)
+	ctx = WithCores(ctx, log.Cores)
+	return ctx
+}
+
+var (
+	defaultLoggerOnce sync.Once
+	defaultLoggerInst *zap.Logger
+)
+
+// defaultLogger lazily builds the package-level logger used as a fallback by With when ctx
+// carries none yet, so enriching a bare context never panics.
+func defaultLogger() *zap.Logger {
+	defaultLoggerOnce.Do(func() {
+		defaultLoggerInst = New(ServiceDefaultConfig).Logger
+	})
+	return defaultLoggerInst
 }
 
-// With adds new logger to context.
+// With adds fields to the logger in ctx and stores the result in a derived context. If ctx
+// carries no logger yet, it falls back to the lazily created default logger rather than
+// panicking. Fields a call chain has already applied to an ancestor context, with the same value,
+// are skipped: a sub-context may re-apply a field it inherited (e.g. a helper that unconditionally
+// tags "request_id" without knowing whether a caller already did) without allocating a new
+// *zap.Logger or growing the context chain for it - copy-on-write, rather than re-wrapping on
+// every call the way a plain Get(ctx).With(fields...) would.
 func With(ctx context.Context, fields ...zap.Field) context.Context {
-	return context.WithValue(ctx, logField, Get(ctx).With(fields...))
+	current := Get(ctx)
+
+	base := current
+	if base == nil {
+		base = defaultLogger()
+	}
+
+	applied, _ := ctx.Value(fieldsField).(map[string]zapcore.Field)
+
+	fresh := make([]zap.Field, 0, len(fields))
+	for _, f := range fields {
+		if existing, ok := applied[f.Key]; !ok || !existing.Equals(f) {
+			fresh = append(fresh, f)
+		}
+	}
+
+	if len(fresh) == 0 && current != nil {
+		return ctx
+	}
+
+	merged := make(map[string]zapcore.Field, len(applied)+len(fresh))
+	for k, f := range applied {
+		merged[k] = f
+	}
+	for _, f := range fresh {
+		merged[f.Key] = f
+	}
+
+	log := base.With(fresh...)
+	ctx = context.WithValue(ctx, logField, log)
+	return context.WithValue(ctx, fieldsField, merged)
 }
 
 // Get gets logger from context.
@@ -62,7 +148,98 @@ func Get(ctx context.Context) *zap.Logger {
 	return log.(*zap.Logger)
 }
 
+// Must returns the logger in ctx, like Get, but panics with a clear message if none is
+// configured. Use it where a logger is mandatory and silently falling back to the package
+// default, as With does, would hide a wiring bug.
+func Must(ctx context.Context) *zap.Logger {
+	log := Get(ctx)
+	if log == nil {
+		panic("logger: no logger configured in context")
+	}
+	return log
+}
+
 // WithLogger adds existing logger to context.
 func WithLogger(ctx context.Context, logger *zap.Logger) context.Context {
 	return context.WithValue(ctx, logField, logger)
 }
+
+// WithLevel adds the atomic level controlling a logger to the context, so it may later be
+// changed at runtime with SetLevel or exposed over HTTP with LevelHandler.
+func WithLevel(ctx context.Context, level zap.AtomicLevel) context.Context {
+	return context.WithValue(ctx, levelField, level)
+}
+
+// GetLevel gets the atomic level from context. ok is false if none is set.
+func GetLevel(ctx context.Context) (level zap.AtomicLevel, ok bool) {
+	l := ctx.Value(levelField)
+	if l == nil {
+		return zap.AtomicLevel{}, false
+	}
+	return l.(zap.AtomicLevel), true
+}
+
+// SetLevel changes, at runtime, the severity of the logger whose atomic level is stored in ctx.
+// It panics if ctx carries no level, since that means the caller never set one up with WithLevel.
+func SetLevel(ctx context.Context, lvl zapcore.Level) {
+	level, ok := GetLevel(ctx)
+	if !ok {
+		panic("logger: no atomic level configured in context")
+	}
+	level.SetLevel(lvl)
+}
+
+// LevelHandler returns an http.Handler exposing the level of the logger stored in ctx over HTTP,
+// modeled on zap.AtomicLevel.ServeHTTP: GET returns the current level, PUT {"level":"debug"}
+// changes it at runtime.
+func LevelHandler(ctx context.Context) http.Handler {
+	level, ok := GetLevel(ctx)
+	if !ok {
+		panic("logger: no atomic level configured in context")
+	}
+	return level
+}
+
+// WithCores adds the MultiCore acting as a logger's root sink registry to the context, so
+// packages may later attach their own sink with AddCore.
+func WithCores(ctx context.Context, cores *MultiCore) context.Context {
+	return context.WithValue(ctx, coresField, cores)
+}
+
+// GetCores gets the MultiCore from context. ok is false if none is set.
+func GetCores(ctx context.Context) (cores *MultiCore, ok bool) {
+	c := ctx.Value(coresField)
+	if c == nil {
+		return nil, false
+	}
+	return c.(*MultiCore), true
+}
+
+// AddCore registers core onto the MultiCore stored in ctx and returns the possibly-updated
+// context together with a function that removes the core again. Packages bolting on an extra sink
+// (remote, file, ...) call this to compose with whatever else is already attached, instead of
+// nesting tees.
+//
+// If ctx was wired with WithLogger alone - the calling convention that predates MultiCore, and
+// still the one New itself doesn't opt a caller into automatically - a MultiCore is lazily spliced
+// in front of the existing root core and attached to the returned context, so a bare
+// "New + WithLogger" caller keeps working without having to also call WithCores (or NewContext).
+func AddCore(ctx context.Context, core zapcore.Core) (context.Context, func()) {
+	cores, ok := GetCores(ctx)
+	if !ok {
+		log := Get(ctx)
+		if log == nil {
+			panic("logger: no logger configured in context")
+		}
+
+		cores = NewMultiCore(log.Core())
+		log = log.WithOptions(zap.WrapCore(func(zapcore.Core) zapcore.Core {
+			return cores
+		}))
+
+		ctx = WithLogger(ctx, log)
+		ctx = WithCores(ctx, cores)
+	}
+
+	return ctx, cores.AddCore(core)
+}
@@ -0,0 +1,112 @@
+package logger
+
+import (
+	"sync"
+
+	"go.uber.org/multierr"
+	"go.uber.org/zap/zapcore"
+)
+
+// MultiCore is a zapcore.Core fanning log entries out to a dynamic set of sinks. Unlike
+// zapcore.NewTee, whose list of cores is fixed at construction, cores may be added to and removed
+// from a MultiCore at runtime, which lets packages such as remote and file bolt on (and later tear
+// down) their own sink without nesting tees.
+type MultiCore struct {
+	mu    sync.RWMutex
+	cores []zapcore.Core
+}
+
+// NewMultiCore creates a MultiCore wrapping the given initial cores.
+func NewMultiCore(cores ...zapcore.Core) *MultiCore {
+	return &MultiCore{cores: cores}
+}
+
+// AddCore registers core with mc and returns a function removing it again.
+func (mc *MultiCore) AddCore(core zapcore.Core) (remove func()) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.cores = append(mc.cores, core)
+
+	return func() {
+		mc.mu.Lock()
+		defer mc.mu.Unlock()
+
+		for i, c := range mc.cores {
+			if c == core {
+				mc.cores = append(mc.cores[:i], mc.cores[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// ReplaceCores atomically swaps the full set of cores for newCores.
+func (mc *MultiCore) ReplaceCores(newCores ...zapcore.Core) {
+	mc.mu.Lock()
+	defer mc.mu.Unlock()
+
+	mc.cores = newCores
+}
+
+// Enabled implements zapcore.Core.
+func (mc *MultiCore) Enabled(lvl zapcore.Level) bool {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	for _, c := range mc.cores {
+		if c.Enabled(lvl) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check implements zapcore.Core.
+func (mc *MultiCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	for _, c := range mc.cores {
+		if c.Enabled(ent.Level) {
+			ce = c.Check(ent, ce)
+		}
+	}
+	return ce
+}
+
+// Write implements zapcore.Core.
+func (mc *MultiCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	var err error
+	for _, c := range mc.cores {
+		err = multierr.Append(err, c.Write(ent, fields))
+	}
+	return err
+}
+
+// Sync implements zapcore.Core.
+func (mc *MultiCore) Sync() error {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	var err error
+	for _, c := range mc.cores {
+		err = multierr.Append(err, c.Sync())
+	}
+	return err
+}
+
+// With implements zapcore.Core.
+func (mc *MultiCore) With(fields []zapcore.Field) zapcore.Core {
+	mc.mu.RLock()
+	defer mc.mu.RUnlock()
+
+	cores := make([]zapcore.Core, len(mc.cores))
+	for i, c := range mc.cores {
+		cores[i] = c.With(fields)
+	}
+	return NewMultiCore(cores...)
+}
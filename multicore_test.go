@@ -0,0 +1,46 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// TestAddCoreAgainstBareWithLogger reproduces the pre-MultiCore calling convention - a context
+// wired with WithLogger alone, the way callers of remote.WithRemote used to build one - and
+// checks AddCore attaches to it instead of panicking.
+func TestAddCoreAgainstBareWithLogger(t *testing.T) {
+	log := New(ToolDefaultConfig)
+	ctx := WithLogger(context.Background(), log.Logger)
+
+	if _, ok := GetCores(ctx); ok {
+		t.Fatal("expected no core registry to be wired yet")
+	}
+
+	ctx, remove := AddCore(ctx, zapcore.NewNopCore())
+	defer remove()
+
+	if _, ok := GetCores(ctx); !ok {
+		t.Fatal("expected AddCore to lazily attach a core registry to ctx")
+	}
+}
+
+// TestAddCoreAgainstNewContext checks AddCore attaches to the registry NewContext already wired
+// in, rather than creating a second, disconnected one.
+func TestAddCoreAgainstNewContext(t *testing.T) {
+	ctx := NewContext(context.Background(), ToolDefaultConfig)
+
+	want, ok := GetCores(ctx)
+	if !ok {
+		t.Fatal("expected NewContext to wire a core registry")
+	}
+
+	ctx, remove := AddCore(ctx, zapcore.NewNopCore())
+	defer remove()
+
+	got, ok := GetCores(ctx)
+	if !ok || got != want {
+		t.Fatal("expected AddCore to reuse the registry NewContext wired in")
+	}
+}
@@ -5,5 +5,6 @@ type Config[T comparable] struct {
 	URL      string
 	User     string
 	Password string
+	Tenant   string
 	Labels   T
 }
@@ -4,10 +4,13 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
+	"reflect"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/outofforest/parallel"
@@ -22,16 +25,29 @@ import (
 const (
 	batchSize     = 100
 	retryInterval = time.Second
+
+	tenantHeader = "X-Scope-OrgID"
 )
 
-// WithRemote adds remote logging to the logger in the context and returns a
-// modified context. The logger in the returned context logs both to stderr and
-// to the remote loki endpoint.
+// LabelMarshaler may be implemented by a Config's Labels type to take full control over how it is
+// turned into Loki stream labels, instead of relying on reflection over its exported fields.
+type LabelMarshaler interface {
+	MarshalLabels() map[string]string
+}
+
+// WithRemote registers a core shipping logs to the remote loki endpoint onto the MultiCore stored
+// in ctx, so they are sent alongside whatever else the logger already writes to (stderr, file,
+// ...). It works whether ctx was wired with logger.NewContext or with the bare
+// logger.WithLogger(ctx, logger.New(cfg).Logger) convention - see logger.AddCore.
 //
-// The caller must call the returned cleanup function after using the logger.
-func WithRemote(ctx context.Context, lokiAddr string) (context.Context, parallel.Task) {
+// The caller must call the returned task, which unregisters the core on shutdown.
+func WithRemote[T comparable](ctx context.Context, config Config[T]) (context.Context, parallel.Task) {
 	conn := &lokiConn{
-		lokiAddr: lokiAddr,
+		lokiAddr: config.URL,
+		user:     config.User,
+		password: config.Password,
+		tenant:   config.Tenant,
+		labels:   labelsOf(config.Labels),
 		buffer:   make(chan interface{}, 1000),
 		lastTime: time.Now().UnixNano(),
 		batch:    make(chan []byte, batchSize),
@@ -39,17 +55,46 @@ func WithRemote(ctx context.Context, lokiAddr string) (context.Context, parallel
 	}
 
 	remoteCore := zapcore.NewCore(zapcore.NewJSONEncoder(logger.EncoderConfig), conn, zap.NewAtomicLevelAt(zap.DebugLevel))
+	ctx, removeCore := logger.AddCore(ctx, remoteCore)
 
-	log := logger.Get(ctx)
-	log = log.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
-		return zapcore.NewTee(core, remoteCore)
-	}))
+	return ctx, func(ctx context.Context) error {
+		defer removeCore()
+		return conn.Run(ctx)
+	}
+}
+
+// labelsOf turns a Config's Labels value into a set of Loki stream labels. If it implements
+// LabelMarshaler that takes precedence, otherwise its exported struct fields are used, lowercased.
+func labelsOf(v any) map[string]string {
+	if lm, ok := v.(LabelMarshaler); ok {
+		return lm.MarshalLabels()
+	}
+
+	labels := map[string]string{}
 
-	return logger.WithLogger(ctx, log), conn.Run
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Struct {
+		return labels
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		if field.PkgPath != "" {
+			// unexported field
+			continue
+		}
+		labels[strings.ToLower(field.Name)] = fmt.Sprint(rv.Field(i).Interface())
+	}
+	return labels
 }
 
 type lokiConn struct {
 	lokiAddr string
+	user     string
+	password string
+	tenant   string
+	labels   map[string]string
 	buffer   chan interface{}
 	lastTime int64
 	batch    chan []byte
@@ -209,11 +254,18 @@ loop:
 			})
 		}
 
+		// lc.labels is applied before the reserved keys, not after, so a Labels field that happens
+		// to lowercase to "level" or "logger" can't clobber the values the rest of the pipeline
+		// (and Loki stream routing) relies on.
+		stream := map[string]any{}
+		for lk, lv := range lc.labels {
+			stream[lk] = lv
+		}
+		stream["level"] = k.Level
+		stream["logger"] = k.Logger
+
 		streams = append(streams, map[string]any{
-			"stream": map[string]any{
-				"level":  k.Level,
-				"logger": k.Logger,
-			},
+			"stream": stream,
 			"values": values,
 		})
 	}
@@ -226,6 +278,12 @@ loop:
 
 			req := must.HTTPRequest(http.NewRequestWithContext(reqCtx, http.MethodPost, lc.lokiAddr+"/loki/api/v1/push", bytes.NewReader(must.Bytes(json.Marshal(map[string]any{"streams": streams})))))
 			req.Header.Set("Content-Type", "application/json")
+			if lc.user != "" || lc.password != "" {
+				req.SetBasicAuth(lc.user, lc.password)
+			}
+			if lc.tenant != "" {
+				req.Header.Set(tenantHeader, lc.tenant)
+			}
 
 			resp, err := http.DefaultClient.Do(req)
 			if err != nil {
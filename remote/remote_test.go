@@ -0,0 +1,127 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type staticLabels struct {
+	Env string
+	App string
+}
+
+func (l staticLabels) MarshalLabels() map[string]string {
+	return map[string]string{"env": l.Env, "app": l.App}
+}
+
+// TestLabelsOfUsesMarshalerWhenImplemented checks that a Labels type implementing LabelMarshaler
+// takes precedence over reflecting its fields.
+func TestLabelsOfUsesMarshalerWhenImplemented(t *testing.T) {
+	got := labelsOf(staticLabels{Env: "prod", App: "api"})
+
+	want := map[string]string{"env": "prod", "app": "api"}
+	if len(got) != len(want) || got["env"] != want["env"] || got["app"] != want["app"] {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+}
+
+type reflectedLabels struct {
+	Env        string
+	unexported string //nolint:unused // verifies unexported fields are skipped
+}
+
+// TestLabelsOfFallsBackToReflection checks that, absent a LabelMarshaler, exported fields of a
+// struct are turned into lowercase-named labels and unexported fields are ignored.
+func TestLabelsOfFallsBackToReflection(t *testing.T) {
+	got := labelsOf(reflectedLabels{Env: "staging", unexported: "hidden"})
+
+	if got["env"] != "staging" {
+		t.Fatalf("expected field Env to become label \"env\", got %v", got)
+	}
+	if _, ok := got["unexported"]; ok {
+		t.Fatal("expected unexported field not to be turned into a label")
+	}
+}
+
+// TestSendSetsBasicAuthAndTenantHeader checks that send() authenticates against the remote
+// endpoint and tags the tenant header whenever the connection was configured with them.
+func TestSendSetsBasicAuthAndTenantHeader(t *testing.T) {
+	var gotUser, gotPassword, gotTenant string
+	var sawBasicAuth bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotPassword, sawBasicAuth = r.BasicAuth()
+		gotTenant = r.Header.Get(tenantHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	lc := &lokiConn{
+		lokiAddr: srv.URL,
+		user:     "alice",
+		password: "secret",
+		tenant:   "tenant-1",
+		labels:   map[string]string{"env": "prod"},
+		batch:    make(chan []byte, 1),
+	}
+	lc.batch <- []byte(`{"ts":"2024-03-04T05:06:07Z","level":"info","msg":"hi"}`)
+
+	if err := lc.send(context.Background()); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	if !sawBasicAuth || gotUser != "alice" || gotPassword != "secret" {
+		t.Fatalf("expected basic auth alice:secret, got %v/%v (set: %v)", gotUser, gotPassword, sawBasicAuth)
+	}
+	if gotTenant != "tenant-1" {
+		t.Fatalf("expected tenant header tenant-1, got %v", gotTenant)
+	}
+}
+
+// TestSendReservesLevelAndLoggerStreamKeys checks that a user label colliding with the "level" or
+// "logger" stream keys (e.g. a Labels field that happens to lowercase to one of them) can't
+// clobber the actual log level/logger name used for Loki stream routing.
+func TestSendReservesLevelAndLoggerStreamKeys(t *testing.T) {
+	var body []byte
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	lc := &lokiConn{
+		lokiAddr: srv.URL,
+		labels:   map[string]string{"level": "bogus", "logger": "bogus"},
+		batch:    make(chan []byte, 1),
+	}
+	lc.batch <- []byte(`{"ts":"2024-03-04T05:06:07Z","level":"warn","logger":"svc","msg":"hi"}`)
+
+	if err := lc.send(context.Background()); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+
+	var payload struct {
+		Streams []struct {
+			Stream map[string]string `json:"stream"`
+		} `json:"streams"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("unmarshaling request body failed: %v", err)
+	}
+	if len(payload.Streams) != 1 {
+		t.Fatalf("expected exactly one stream, got %d", len(payload.Streams))
+	}
+
+	stream := payload.Streams[0].Stream
+	if stream["level"] != "warn" {
+		t.Fatalf("expected a colliding label not to clobber the real level, got %v", stream["level"])
+	}
+	if stream["logger"] != "svc" {
+		t.Fatalf("expected a colliding label not to clobber the real logger name, got %v", stream["logger"])
+	}
+}
@@ -0,0 +1,52 @@
+package logger
+
+import (
+	"context"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// TestWithSkipsFieldsAlreadyAppliedByAncestor reproduces a call chain where a descendant
+// re-applies a field an ancestor context already carries with the same value (e.g. a shared
+// helper tagging "request_id" without knowing whether a caller already did): With must not
+// allocate a new *zap.Logger or grow the context chain for it.
+func TestWithSkipsFieldsAlreadyAppliedByAncestor(t *testing.T) {
+	ctx := WithLogger(context.Background(), New(ToolDefaultConfig).Logger)
+
+	ctx1 := With(ctx, zap.String("req", "abc"))
+	if ctx1 == ctx {
+		t.Fatal("expected With to wrap the context when applying a new field")
+	}
+
+	ctx2 := With(ctx1, zap.String("req", "abc"))
+	if ctx2 != ctx1 {
+		t.Fatal("expected With to return the same context when the field was already applied with the same value")
+	}
+	if Get(ctx2) != Get(ctx1) {
+		t.Fatal("expected With not to allocate a new logger when the field was already applied with the same value")
+	}
+}
+
+// TestWithReappliesChangedField checks that a field re-applied with a different value is not
+// mistaken for a no-op.
+func TestWithReappliesChangedField(t *testing.T) {
+	ctx := WithLogger(context.Background(), New(ToolDefaultConfig).Logger)
+
+	ctx1 := With(ctx, zap.String("req", "abc"))
+	ctx2 := With(ctx1, zap.String("req", "def"))
+
+	if ctx2 == ctx1 {
+		t.Fatal("expected With to wrap the context again when the field's value changed")
+	}
+}
+
+// TestWithFallsBackToDefaultLogger checks that enriching a bare context, with no logger
+// installed, falls back to the package default instead of panicking.
+func TestWithFallsBackToDefaultLogger(t *testing.T) {
+	ctx := With(context.Background(), zap.String("req", "abc"))
+
+	if Get(ctx) == nil {
+		t.Fatal("expected With to install a fallback logger into ctx")
+	}
+}